@@ -0,0 +1,119 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"sync"
+
+	rpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultBatchMaxSize limits the number of JSON-RPC calls coalesced into a single
+// batch request so a single GraphQL query can not overwhelm the upstream node,
+// unless overridden by config.Lachesis.BatchMaxSize.
+const defaultBatchMaxSize = 100
+
+// defaultBatchConcurrency bounds how many batch chunks of a single BatchCall are
+// in flight at once, unless overridden by config.Lachesis.BatchConcurrency.
+const defaultBatchConcurrency = 4
+
+// BatchCallItem represents a single JSON-RPC call to be executed as a part of a batch,
+// with its decoded result delivered into Out once the batch completes.
+type BatchCallItem struct {
+	Method string
+	Args   []interface{}
+	Out    interface{}
+	Error  error
+}
+
+// BatchCall executes a list of JSON-RPC calls in as few round trips as possible,
+// chunking the list into batches of at most chain.batchMaxSize items and running
+// up to chain.batchConcurrency chunks concurrently, returning per-item errors
+// through each BatchCallItem.Error field and the first chunk-level error, if any.
+func (chain *ChainBridge) BatchCall(items []BatchCallItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	maxSize := chain.batchMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultBatchMaxSize
+	}
+	concurrency := chain.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var chunks [][]BatchCallItem
+	for start := 0; start < len(items); start += maxSize {
+		end := start + maxSize
+		if end > len(items) {
+			end = len(items)
+		}
+		chunks = append(chunks, items[start:end])
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	sem := make(chan struct{}, concurrency)
+
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(chunk []BatchCallItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := chain.runBatchChunk(chunk); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(chunk)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// runBatchChunk sends a single batch of at most batchMaxSize items to one endpoint.
+func (chain *ChainBridge) runBatchChunk(chunk []BatchCallItem) error {
+	ep := chain.dispatcher.pickRead()
+
+	elems := make([]rpc.BatchElem, len(chunk))
+	for i := range chunk {
+		elems[i] = rpc.BatchElem{
+			Method: chunk[i].Method,
+			Args:   chunk[i].Args,
+			Result: chunk[i].Out,
+		}
+	}
+
+	if err := ep.rpc.BatchCall(elems); err != nil {
+		chain.log.Errorf("batch call of %d items failed on endpoint %s; %s", len(chunk), ep.url, err.Error())
+		ep.recordFailure()
+		ep.setHealthy(false)
+		return err
+	}
+	ep.recordSuccess()
+
+	for i := range elems {
+		chunk[i].Error = elems[i].Error
+	}
+	return nil
+}
@@ -18,15 +18,19 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	etc "github.com/ethereum/go-ethereum/core/types"
 )
 
 // chainHeadsObserverSubscribeTick represents the time between subscription attempts.
 const chainHeadsObserverSubscribeTick = 30 * time.Second
 
-// observeBlocks collects new blocks from the blockchain network
-// and posts them into the proxy channel for processing.
+// observeBlocks collects new blocks from the blockchain network, invalidates the
+// block-tagged entries of the contract read cache, and posts the blocks into
+// the proxy channel for processing.
 func (chain *ChainBridge) observeBlocks() {
 	var sub ethereum.Subscription
+	raw := make(chan *etc.Header, rpcHeadProxyChannelCapacity)
+
 	defer func() {
 		if sub != nil {
 			sub.Unsubscribe()
@@ -35,7 +39,7 @@ func (chain *ChainBridge) observeBlocks() {
 		chain.wg.Done()
 	}()
 
-	sub = chain.blockSubscription()
+	sub = chain.blockSubscription(raw)
 	for {
 		// re-subscribe if the subscription ref is not valid
 		if sub == nil {
@@ -44,7 +48,7 @@ func (chain *ChainBridge) observeBlocks() {
 			case <-chain.sigClose:
 				return
 			case <-tm.C:
-				sub = chain.blockSubscription()
+				sub = chain.blockSubscription(raw)
 				continue
 			}
 		}
@@ -53,20 +57,40 @@ func (chain *ChainBridge) observeBlocks() {
 		select {
 		case <-chain.sigClose:
 			return
+		case h := <-raw:
+			chain.invalidateBlockTagged()
+			select {
+			case chain.headers <- h:
+			default:
+				chain.log.Errorf("block proxy channel is full, dropping head #%d", uint64(h.Number.Uint64()))
+			}
 		case err := <-sub.Err():
 			chain.log.Errorf("block subscription failed; %s", err.Error())
+
+			// the endpoint we were subscribed to went bad; move the sticky
+			// subscription target elsewhere before the next subscribe attempt
+			chain.dispatcher.resubscribeElsewhere(chain.dispatcher.pickSticky())
 			sub = nil
 		}
 	}
 }
 
 // blockSubscription provides a subscription for new blocks received
-// by the connected blockchain node.
-func (chain *ChainBridge) blockSubscription() ethereum.Subscription {
-	sub, err := chain.rpc.EthSubscribe(context.Background(), chain.headers, "newHeads")
+// by the connected blockchain node, delivered into the given channel.
+func (chain *ChainBridge) blockSubscription(into chan *etc.Header) ethereum.Subscription {
+	sub, err := chain.dispatcher.EthSubscribe(context.Background(), into, "newHeads")
 	if err != nil {
 		chain.log.Criticalf("can not observe new blocks; %s", err.Error())
 		return nil
 	}
 	return sub
 }
+
+// invalidateBlockTagged drops cache entries which are only valid for the block they
+// were computed at, such as the current list of active DeFi tokens, and clears the
+// whole cache once the sealed epoch has advanced so epoch-scoped entries from prior
+// epochs don't accumulate forever (see evictStaleEpochEntries).
+func (chain *ChainBridge) invalidateBlockTagged() {
+	chain.cache.Invalidate(defiTokensCacheKey)
+	chain.evictStaleEpochEntries()
+}
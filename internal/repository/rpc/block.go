@@ -32,7 +32,7 @@ const (
 // of the blockchain. It returns nil if the block height can not be pulled.
 func (chain *ChainBridge) MustBlockHeight() *big.Int {
 	var val hexutil.Big
-	if err := chain.rpc.Call(&val, "eth_blockNumber"); err != nil {
+	if err := chain.dispatcher.Call(&val, "eth_blockNumber"); err != nil {
 		chain.log.Errorf("failed block height check; %s", err.Error())
 		return nil
 	}
@@ -47,7 +47,7 @@ func (chain *ChainBridge) BlockHeight() (*hexutil.Big, error) {
 	// call for data
 	var height hexutil.Big
 
-	err := chain.rpc.Call(&height, "eth_blockNumber")
+	err := chain.dispatcher.Call(&height, "eth_blockNumber")
 	if err != nil {
 		chain.log.Error("block height could not be obtained")
 		return nil, err
@@ -66,7 +66,7 @@ func (chain *ChainBridge) Block(numTag *string) (*types.Block, error) {
 
 	// call for data
 	var block types.Block
-	err := chain.rpc.Call(&block, "eth_getBlockByNumber", numTag, false)
+	err := chain.dispatcher.Call(&block, "eth_getBlockByNumber", numTag, false)
 	if err != nil {
 		chain.log.Error("block could not be extracted")
 		return nil, err
@@ -91,7 +91,7 @@ func (chain *ChainBridge) BlockByHash(hash *string) (*types.Block, error) {
 
 	// call for data
 	var block types.Block
-	err := chain.rpc.Call(&block, "eth_getBlockByHash", hash, false)
+	err := chain.dispatcher.Call(&block, "eth_getBlockByHash", hash, false)
 	if err != nil {
 		chain.log.Error("block could not be extracted")
 		return nil, err
@@ -108,3 +108,33 @@ func (chain *ChainBridge) BlockByHash(hash *string) (*types.Block, error) {
 		uint64(block.Number), time.Unix(int64(block.TimeStamp), 0).String(), *hash)
 	return &block, nil
 }
+
+// BlocksByNumbers loads a range of blocks in a single round trip to the node
+// by coalescing the individual eth_getBlockByNumber calls into one batch call.
+func (chain *ChainBridge) BlocksByNumbers(numTags []string) ([]*types.Block, error) {
+	// keep track of the operation
+	chain.log.Debugf("loading %d blocks in a batch", len(numTags))
+
+	blocks := make([]types.Block, len(numTags))
+	items := make([]BatchCallItem, len(numTags))
+	for i, numTag := range numTags {
+		items[i] = BatchCallItem{Method: "eth_getBlockByNumber", Args: []interface{}{numTag, false}, Out: &blocks[i]}
+	}
+
+	if err := chain.BatchCall(items); err != nil {
+		chain.log.Error("blocks batch could not be extracted")
+		return nil, err
+	}
+
+	// collect the decoded blocks, keeping the first per-item error we encounter
+	out := make([]*types.Block, len(numTags))
+	for i := range items {
+		if items[i].Error != nil {
+			chain.log.Errorf("block [%s] could not be extracted; %s", numTags[i], items[i].Error.Error())
+			return nil, items[i].Error
+		}
+		out[i] = &blocks[i]
+	}
+
+	return out, nil
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"galaxy-graphql/internal/config"
 	"galaxy-graphql/internal/logger"
+	"galaxy-graphql/internal/repository/rpc/cache"
 	"galaxy-graphql/internal/repository/rpc/contracts"
 	"strings"
 	"sync"
@@ -24,7 +25,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	etc "github.com/ethereum/go-ethereum/core/types"
-	eth "github.com/ethereum/go-ethereum/ethclient"
 	rpc "github.com/ethereum/go-ethereum/rpc"
 	"golang.org/x/sync/singleflight"
 )
@@ -34,16 +34,23 @@ const rpcHeadProxyChannelCapacity = 10000
 
 // ChainBridge represents Lachesis RPC abstraction layer.
 type ChainBridge struct {
-	rpc *rpc.Client
-	eth *eth.Client
-	log logger.Logger
-	cg  *singleflight.Group
+	dispatcher *endpointDispatcher
+	log        logger.Logger
+	cg         *singleflight.Group
 
 	// fMintCfg represents the configuration of the fMint protocol
 	sigConfig     *config.ServerSignature
 	sfcConfig     *config.Staking
 	uniswapConfig *config.DeFiUniswap
 
+	// rpcGasCap bounds the gas amount considered during client-side gas estimation,
+	// mirroring the upstream node's --rpc.gascap.
+	rpcGasCap uint64
+
+	// batchMaxSize/batchConcurrency bound BatchCall's chunking and fan-out; see batch.go.
+	batchMaxSize     int
+	batchConcurrency int
+
 	// extended minter config
 	fMintCfg fMintConfig
 	fLendCfg fLendConfig
@@ -51,6 +58,15 @@ type ChainBridge struct {
 	// common contracts
 	sfcAbi      *abi.ABI
 	sfcContract *contracts.SfcContract
+	sfc         SFC
+	registry    *contracts.Registry
+	filters     *FilterAPI
+	cache       *cache.Cache
+	oracle      *GasPriceOracle
+
+	// lastSealedEpoch is the sealed epoch the cache was last evicted for; see
+	// evictStaleEpochEntries in contract_cache.go.
+	lastSealedEpoch uint64
 
 	// received blocks proxy
 	wg       *sync.WaitGroup
@@ -60,7 +76,7 @@ type ChainBridge struct {
 
 // New creates new Lachesis RPC connection bridge.
 func New(cfg *config.Config, log logger.Logger) (*ChainBridge, error) {
-	cli, con, err := connect(cfg, log)
+	ed, err := connect(cfg, log)
 	if err != nil {
 		log.Criticalf("can not open connection; %s", err.Error())
 		return nil, err
@@ -68,26 +84,57 @@ func New(cfg *config.Config, log logger.Logger) (*ChainBridge, error) {
 
 	// build the bridge structure using the con we have
 	br := &ChainBridge{
-		rpc: cli,
-		eth: con,
-		log: log,
-		cg:  new(singleflight.Group),
+		dispatcher: ed,
+		log:        log,
+		cg:         new(singleflight.Group),
 
 		// special configuration options below this line
 		sigConfig:     &cfg.MySignature,
 		sfcConfig:     &cfg.Staking,
 		uniswapConfig: &cfg.DeFi.Uniswap,
+		rpcGasCap:     defaultRPCGasCap,
+
+		batchMaxSize:     defaultBatchMaxSize,
+		batchConcurrency: defaultBatchConcurrency,
 		fMintCfg: fMintConfig{
 			addressProvider: cfg.DeFi.FMint.AddressProvider,
 		},
 		fLendCfg: fLendConfig{lendigPoolAddress: cfg.DeFi.FLend.LendingPool},
 
+		// runtime-loaded ABI registry, used to onboard new contract versions
+		// without regenerating the abigen bindings
+		registry: contracts.NewRegistry(),
+
+		// contract read cache; see contract_cache.go for its usage
+		cache: cache.New(),
+
 		// configure block observation loop
 		wg:       new(sync.WaitGroup),
 		sigClose: make(chan bool, 1),
 		headers:  make(chan *etc.Header, rpcHeadProxyChannelCapacity),
 	}
 
+	// load any runtime-provided contract ABIs, if configured; a missing or empty
+	// directory is not fatal since most contracts are still bound via abigen
+	if cfg.Contracts.AbiDir != "" {
+		if err := br.registry.LoadDir(cfg.Contracts.AbiDir); err != nil {
+			log.Errorf("can not load contract ABI registry; %s", err.Error())
+		}
+	}
+
+	// allow operators to override the default RPC gas cap
+	if cfg.Lachesis.RPCGasCap > 0 {
+		br.rpcGasCap = cfg.Lachesis.RPCGasCap
+	}
+
+	// allow operators to override the default batch call chunking/fan-out
+	if cfg.Lachesis.BatchMaxSize > 0 {
+		br.batchMaxSize = cfg.Lachesis.BatchMaxSize
+	}
+	if cfg.Lachesis.BatchConcurrency > 0 {
+		br.batchConcurrency = cfg.Lachesis.BatchConcurrency
+	}
+
 	// inform about the local address of the API node
 	log.Noticef("using signature address %s", br.sigConfig.Address.String())
 
@@ -97,34 +144,40 @@ func New(cfg *config.Config, log logger.Logger) (*ChainBridge, error) {
 	return br, nil
 }
 
-// connect opens connections we need to communicate with the blockchain node.
-func connect(cfg *config.Config, log logger.Logger) (*rpc.Client, *eth.Client, error) {
-	// log what we do
-	log.Debugf("connecting blockchain node at %s", cfg.Lachesis.Url)
-
-	// try to establish a connection
-	client, err := rpc.Dial(cfg.Lachesis.Url)
-	if err != nil {
-		log.Critical(err)
-		return nil, nil, err
+// endpointUrls resolves the list of Lachesis/Opera URLs to dial from the configuration,
+// keeping the single-URL configuration working for backward compatibility.
+func endpointUrls(cfg *config.Config) []string {
+	if len(cfg.Lachesis.Urls) > 0 {
+		return cfg.Lachesis.Urls
 	}
+	return []string{cfg.Lachesis.Url}
+}
 
-	// try to establish a for smart contract interaction
-	con, err := eth.Dial(cfg.Lachesis.Url)
+// connect opens connections we need to communicate with the blockchain node(s).
+func connect(cfg *config.Config, log logger.Logger) (*endpointDispatcher, error) {
+	urls := endpointUrls(cfg)
+
+	// log what we do
+	log.Debugf("connecting blockchain node(s) at %s", strings.Join(urls, ", "))
+
+	ed, err := newEndpointDispatcher(urls, log)
 	if err != nil {
 		log.Critical(err)
-		return nil, nil, err
+		return nil, err
 	}
 
 	// log
-	log.Notice("node connection open")
-	return client, con, nil
+	log.Noticef("node connection open on %d endpoint(s)", len(ed.endpoints))
+	return ed, nil
 }
 
 // run starts the bridge threads required to collect blockchain data.
 func (chain *ChainBridge) run() {
 	chain.wg.Add(1)
 	go chain.observeBlocks()
+
+	chain.dispatcher.run(chain.sigClose, chain.wg)
+	chain.runGasOracle()
 }
 
 // terminate kills the bridge threads to end the bridge gracefully.
@@ -140,16 +193,15 @@ func (chain *ChainBridge) Close() {
 	chain.terminate()
 
 	// do we have a connection?
-	if chain.rpc != nil {
-		chain.rpc.Close()
-		chain.eth.Close()
+	if chain.dispatcher != nil {
+		chain.dispatcher.Close()
 		chain.log.Info("blockchain connections are closed")
 	}
 }
 
-// Connection returns open Opera/Lachesis connection.
+// Connection returns open Opera/Lachesis connection of the primary endpoint.
 func (chain *ChainBridge) Connection() *rpc.Client {
-	return chain.rpc
+	return chain.dispatcher.primary().rpc
 }
 
 // DefaultCallOpts creates a default record for call options.
@@ -172,7 +224,7 @@ func (chain *ChainBridge) SfcContract() *contracts.SfcContract {
 	if nil == chain.sfcContract {
 		// instantiate the contract and display its name
 		var err error
-		chain.sfcContract, err = contracts.NewSfcContract(chain.sfcConfig.SFCContract, chain.eth)
+		chain.sfcContract, err = contracts.NewSfcContract(chain.sfcConfig.SFCContract, chain.dispatcher.primary().eth)
 		if err != nil {
 			chain.log.Criticalf("failed to instantiate SFC contract; %s", err.Error())
 			panic(err)
@@ -181,9 +233,15 @@ func (chain *ChainBridge) SfcContract() *contracts.SfcContract {
 	return chain.sfcContract
 }
 
-// SfcAbi returns a parse ABI of the AFC contract.
+// SfcAbi returns a parse ABI of the AFC contract, preferring a version loaded into
+// the runtime contract registry over the ABI baked into the abigen bindings.
 func (chain *ChainBridge) SfcAbi() *abi.ABI {
 	if nil == chain.sfcAbi {
+		if ab, err := chain.registry.ABI("sfc"); err == nil {
+			chain.sfcAbi = ab
+			return chain.sfcAbi
+		}
+
 		ab, err := abi.JSON(strings.NewReader(contracts.SfcContractABI))
 		if err != nil {
 			chain.log.Criticalf("failed to parse SFC contract ABI; %s", err.Error())
@@ -194,6 +252,17 @@ func (chain *ChainBridge) SfcAbi() *abi.ABI {
 	return chain.sfcAbi
 }
 
+// Registry returns the runtime-loaded contract ABI registry.
+func (chain *ChainBridge) Registry() *contracts.Registry {
+	return chain.registry
+}
+
+// Endpoints returns a point-in-time health snapshot of every Lachesis/Opera
+// endpoint the bridge is connected to, so operators can expose it to GraphQL.
+func (chain *ChainBridge) Endpoints() []EndpointHealth {
+	return chain.dispatcher.health()
+}
+
 // ObservedBlockProxy provides a channel fed with new headers observed
 // by the connected blockchain node.
 func (chain *ChainBridge) ObservedBlockProxy() chan *etc.Header {
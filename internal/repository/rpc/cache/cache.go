@@ -0,0 +1,86 @@
+// Package cache provides a small TTL cache with block-tagged invalidation used
+// by the rpc package to avoid re-reading values from the upstream Lachesis node
+// that either never change or change only on epoch/block boundaries.
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entry represents a single cached value, with expires left zero for values
+// that are cached until explicitly invalidated (e.g. on a block-tag change).
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// expired tells if the entry is no longer valid.
+func (e *entry) expired() bool {
+	return !e.expires.IsZero() && time.Now().After(e.expires)
+}
+
+// Cache is a concurrency-safe TTL cache keyed by string; block-tagged entries
+// are invalidated by the caller, key by key via Invalidate or all at once via Clear.
+type Cache struct {
+	mu    sync.RWMutex
+	items map[string]entry
+
+	hits   uint64
+	misses uint64
+}
+
+// New creates an empty cache.
+func New() *Cache {
+	return &Cache{items: make(map[string]entry)}
+}
+
+// Get returns a previously cached value, reporting a miss if the key is
+// unknown or its TTL has expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok || e.expired() {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	return e.value, true
+}
+
+// Set stores a value that never expires on its own, only via Invalidate/Clear.
+func (c *Cache) Set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = entry{value: value}
+}
+
+// SetTTL stores a value that expires automatically after the given duration.
+func (c *Cache) SetTTL(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = entry{value: value, expires: time.Now().Add(ttl)}
+}
+
+// Invalidate drops a single cached key.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Clear drops every cached key, e.g. when the block tag used for invalidation changes.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[string]entry)
+}
+
+// Stats returns the accumulated hit/miss counters of the cache.
+func (c *Cache) Stats() (hits uint64, misses uint64) {
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses)
+}
@@ -0,0 +1,80 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// cachedForever loads a value from the contract read cache, computing and storing
+// it on a miss. Concurrent callers for the same key coalesce behind a single
+// in-flight fetch via the bridge singleflight group.
+func (chain *ChainBridge) cachedForever(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if v, ok := chain.cache.Get(key); ok {
+		return v, nil
+	}
+
+	v, err, _ := chain.cg.Do("cache:"+key, func() (interface{}, error) {
+		if v, ok := chain.cache.Get(key); ok {
+			return v, nil
+		}
+
+		v, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		chain.cache.Set(key, v)
+		return v, nil
+	})
+	return v, err
+}
+
+// sfcParamKey builds a cache key for a constant SFC parameter, scoped to the
+// currently detected SFC contract version so an SFC upgrade invalidates it.
+func (chain *ChainBridge) sfcParamKey(name string) (string, error) {
+	ver, err := chain.SfcVersion()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sfc:%s:%d", name, ver), nil
+}
+
+// epochScopedKey builds a cache key for a value that only changes on epoch
+// boundaries, scoped to the current sealed epoch.
+func (chain *ChainBridge) epochScopedKey(name string) (string, error) {
+	epoch, err := chain.CurrentSealedEpoch()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:epoch:%d", name, epoch), nil
+}
+
+// evictStaleEpochEntries clears the whole contract read cache once the sealed
+// epoch advances, so epoch- and version-scoped entries (sfcParamKey,
+// epochScopedKey) from prior epochs don't accumulate in the cache forever.
+// Every call reads the current sealed epoch from the SFC contract and swaps it
+// into lastSealedEpoch; the cache.Clear() only happens when that value changed.
+func (chain *ChainBridge) evictStaleEpochEntries() {
+	epoch, err := chain.CurrentSealedEpoch()
+	if err != nil {
+		chain.log.Errorf("can not load sealed epoch for cache eviction; %s", err.Error())
+		return
+	}
+
+	if atomic.SwapUint64(&chain.lastSealedEpoch, uint64(epoch)) != uint64(epoch) {
+		chain.cache.Clear()
+	}
+}
@@ -20,21 +20,33 @@ import (
 	"galaxy-graphql/internal/repository/rpc/contracts"
 	"galaxy-graphql/internal/types"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
+// defiTokensCacheKey is block-tagged via invalidateBlockTagged(), since the
+// set of active DeFi tokens only changes when a block lands.
+const defiTokensCacheKey = "defi:tokens"
+
 // DefiTokens resolves list of DeFi tokens available for the DeFi functions.
+// The result is cached and invalidated whenever a new block is observed.
 func (chain *ChainBridge) DefiTokens() ([]types.DefiToken, error) {
-	// connect the contract
-	contract, err := chain.fMintCfg.tokenRegistryContract()
+	v, err := chain.cachedForever(defiTokensCacheKey, func() (interface{}, error) {
+		// connect the contract
+		contract, err := chain.fMintCfg.tokenRegistryContract()
+		if err != nil {
+			return nil, err
+		}
+
+		return chain.defiTokensList(contract)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return chain.defiTokensList(contract)
+	return v.([]types.DefiToken), nil
 }
 
 // DefiTokenList creates a list of addresses / identifiers of all the ERC20 tokens
@@ -110,6 +122,12 @@ func (chain *ChainBridge) defiTokenDetail(contract *contracts.DefiFMintTokenRegi
 }
 
 // defiTokensList loads list of DeFi tokens from the smart contract.
+//
+// The per-token Tokens(...) lookups are fanned out with bounded concurrency
+// (chain.batchConcurrency) rather than coalesced into a single BatchCall: the
+// abigen-bound contract does not expose the ABI/address a raw eth_call batch
+// would need to pack, so true JSON-RPC batching of these specific calls would
+// require a multicall contract or a registry-loaded ABI for this contract.
 func (chain *ChainBridge) defiTokensList(contract *contracts.DefiFMintTokenRegistry) ([]types.DefiToken, error) {
 	// get tge list of addresses
 	al, err := chain.defiTokenAddressList(contract.TokensCount, contract.TokensList)
@@ -118,21 +136,41 @@ func (chain *ChainBridge) defiTokensList(contract *contracts.DefiFMintTokenRegis
 		return nil, err
 	}
 
-	// make a container for tokens
-	list := make([]types.DefiToken, 0)
+	concurrency := chain.batchConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	tokens := make([]*types.DefiToken, len(al))
+	errs := make([]error, len(al))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
 
-	// load all the tokens in the contract
 	for i, addr := range al {
-		// decode the token
-		tk, err := chain.defiTokenDetail(contract, &addr)
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, addr common.Address) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			tokens[i], errs[i] = chain.defiTokenDetail(contract, &addr)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	// make a container for tokens
+	list := make([]types.DefiToken, 0, len(al))
+	for i, err := range errs {
 		if err != nil {
 			chain.log.Errorf("invalid token #%d; %s", i, err.Error())
 			return nil, err
 		}
 
 		// add the token if it's still active
-		if tk.IsActive {
-			list = append(list, *tk)
+		if tokens[i].IsActive {
+			list = append(list, *tokens[i])
 		}
 	}
 
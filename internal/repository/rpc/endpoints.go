@@ -0,0 +1,551 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"galaxy-graphql/internal/logger"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	eth "github.com/ethereum/go-ethereum/ethclient"
+	rpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// endpointHealthCheckTick represents the delay between consecutive endpoint health checks.
+const endpointHealthCheckTick = 15 * time.Second
+
+// endpointLagLimit represents the maximum number of blocks an endpoint is allowed
+// to lag behind the most advanced known endpoint before it's considered unhealthy.
+const endpointLagLimit = 5
+
+// circuitFailureWindow is the rolling window over which consecutive call failures
+// of a single endpoint are counted towards tripping its circuit breaker.
+const circuitFailureWindow = time.Minute
+
+// circuitFailureThreshold is the number of failures within circuitFailureWindow
+// that open the circuit breaker of an endpoint, taking it out of rotation.
+const circuitFailureThreshold = 5
+
+// circuitCooldown is how long an open circuit breaker waits before letting a
+// single half-open probe call through again.
+const circuitCooldown = 30 * time.Second
+
+// callRetryBackoffBase is the base delay between retries of a failed call on
+// the next endpoint; the actual delay is jittered to avoid a retry stampede.
+const callRetryBackoffBase = 50 * time.Millisecond
+
+// circuitState represents the state of an endpoint's circuit breaker.
+type circuitState int32
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// transportKind classifies an endpoint by the transport it was dialed with, so
+// the dispatcher can prefer IPC for subscriptions and heavy calls.
+type transportKind int
+
+const (
+	transportIPC transportKind = iota
+	transportWS
+	transportHTTP
+)
+
+// classifyTransport derives the transport kind of an endpoint from its dial URL.
+func classifyTransport(url string) transportKind {
+	switch {
+	case strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		return transportHTTP
+	case strings.HasPrefix(url, "ws://"), strings.HasPrefix(url, "wss://"):
+		return transportWS
+	default:
+		return transportIPC
+	}
+}
+
+// heavyMethodPrefixes lists JSON-RPC methods that are expensive enough on the node
+// side that the dispatcher prefers running them over the local IPC socket, if any.
+var heavyMethodPrefixes = []string{"debug_"}
+
+// isHeavyMethod reports whether method should preferably be routed to the IPC
+// transport rather than a remote HTTP/WS peer.
+func isHeavyMethod(method string) bool {
+	if method == "eth_estimateGas" {
+		return true
+	}
+	for _, prefix := range heavyMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpoint represents a single Lachesis/Opera RPC endpoint managed by the dispatcher.
+type endpoint struct {
+	url       string
+	transport transportKind
+	rpc       *rpc.Client
+	eth       *eth.Client
+	healthy   int32  // atomic bool; 1 = healthy, 0 = unhealthy
+	height    uint64 // atomic; the last block height observed on this endpoint
+
+	mu       sync.Mutex
+	circuit  circuitState
+	failures []time.Time
+	openedAt time.Time
+}
+
+// isHealthy returns true if the endpoint is currently considered usable.
+func (ep *endpoint) isHealthy() bool {
+	return atomic.LoadInt32(&ep.healthy) == 1
+}
+
+// setHealthy updates the health flag of the endpoint.
+func (ep *endpoint) setHealthy(state bool) {
+	if state {
+		atomic.StoreInt32(&ep.healthy, 1)
+		return
+	}
+	atomic.StoreInt32(&ep.healthy, 0)
+}
+
+// canRoute reports whether a call could currently reach this endpoint, without
+// claiming the single half-open probe slot of an open circuit past its cooldown.
+// Use this for read-only selection scans (pickRead, pickIPC, refreshSticky);
+// use claimProbe instead at the point a call is actually about to be dispatched.
+func (ep *endpoint) canRoute() bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	switch ep.circuit {
+	case circuitClosed:
+		return true
+	case circuitOpen:
+		return time.Since(ep.openedAt) >= circuitCooldown
+	default: // circuitHalfOpen; a probe is already in flight
+		return false
+	}
+}
+
+// claimProbe reports whether a call is allowed to reach this endpoint right now,
+// and if the circuit is open past its cooldown, atomically transitions it to
+// half-open and claims this call as the single probe allowed through; concurrent
+// callers are held back until that probe reports back success or failure. Call
+// this only at the point a call is actually dispatched, never during a selection
+// scan, or a scan alone could consume the half-open probe slot without any call
+// being issued.
+func (ep *endpoint) claimProbe() bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	switch ep.circuit {
+	case circuitClosed:
+		return true
+	case circuitOpen:
+		if time.Since(ep.openedAt) >= circuitCooldown {
+			ep.circuit = circuitHalfOpen
+			return true // this caller becomes the single half-open probe
+		}
+		return false
+	default: // circuitHalfOpen; a probe is already in flight, hold other callers back
+		return false
+	}
+}
+
+// recordSuccess closes the circuit breaker and forgets prior failures.
+func (ep *endpoint) recordSuccess() {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.failures = nil
+	ep.circuit = circuitClosed
+}
+
+// recordFailure accounts a call failure towards the rolling failure window and
+// opens the circuit breaker once circuitFailureThreshold is reached.
+func (ep *endpoint) recordFailure() {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	now := time.Now()
+	ep.failures = append(ep.failures, now)
+
+	cutoff := now.Add(-circuitFailureWindow)
+	trimmed := ep.failures[:0]
+	for _, t := range ep.failures {
+		if t.After(cutoff) {
+			trimmed = append(trimmed, t)
+		}
+	}
+	ep.failures = trimmed
+
+	if ep.circuit == circuitHalfOpen || len(ep.failures) >= circuitFailureThreshold {
+		ep.circuit = circuitOpen
+		ep.openedAt = now
+	}
+}
+
+// EndpointHealth is a point-in-time snapshot of a single endpoint's health, exposed
+// for operational GraphQL queries via ChainBridge.Endpoints().
+type EndpointHealth struct {
+	Url       string
+	Transport string
+	Healthy   bool
+	Circuit   string
+	Height    uint64
+}
+
+// String renders a transportKind the way operators refer to it.
+func (k transportKind) String() string {
+	switch k {
+	case transportIPC:
+		return "ipc"
+	case transportWS:
+		return "ws"
+	default:
+		return "http"
+	}
+}
+
+// String renders a circuitState the way operators refer to it.
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// endpointDispatcher routes RPC calls across a set of healthy endpoints, providing
+// round-robin selection for stateless reads, IPC-affinity for heavy calls and
+// subscriptions, and per-endpoint circuit breakers to demote flapping peers.
+type endpointDispatcher struct {
+	log       logger.Logger
+	endpoints []*endpoint
+	next      uint32 // atomic round-robin cursor
+
+	mu     sync.RWMutex
+	sticky *endpoint
+
+	// metrics accumulates rpc_requests_total/rpc_duration_seconds-equivalent
+	// counters for every call routed through Call/CallContext.
+	metrics *callMetrics
+}
+
+// newEndpointDispatcher dials every URL in the list and builds a dispatcher on top of them.
+// At least one endpoint must dial successfully, or the dispatcher is not created.
+func newEndpointDispatcher(urls []string, log logger.Logger) (*endpointDispatcher, error) {
+	ed := &endpointDispatcher{log: log, metrics: newCallMetrics()}
+
+	for _, url := range urls {
+		cli, err := rpc.Dial(url)
+		if err != nil {
+			log.Errorf("can not dial endpoint %s; %s", url, err.Error())
+			continue
+		}
+
+		con := eth.NewClient(cli)
+		ed.endpoints = append(ed.endpoints, &endpoint{
+			url:       url,
+			transport: classifyTransport(url),
+			rpc:       cli,
+			eth:       con,
+			healthy:   1,
+		})
+	}
+
+	if len(ed.endpoints) == 0 {
+		return nil, fmt.Errorf("no usable Lachesis/Opera endpoint available")
+	}
+
+	ed.sticky = ed.pickIPC()
+	if ed.sticky == nil {
+		ed.sticky = ed.endpoints[0]
+	}
+	return ed, nil
+}
+
+// run starts the periodic health check loop for all the endpoints of the dispatcher.
+func (ed *endpointDispatcher) run(sigClose chan bool, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		tick := time.NewTicker(endpointHealthCheckTick)
+		defer tick.Stop()
+
+		ed.healthCheck()
+		for {
+			select {
+			case <-sigClose:
+				return
+			case <-tick.C:
+				ed.healthCheck()
+			}
+		}
+	}()
+}
+
+// healthCheck probes every known endpoint via eth_blockNumber and marks endpoints
+// lagging behind the best known height by more than endpointLagLimit as unhealthy.
+func (ed *endpointDispatcher) healthCheck() {
+	var best uint64
+	for _, ep := range ed.endpoints {
+		height, err := ed.probe(ep)
+		if err != nil {
+			ed.log.Errorf("endpoint %s failed health check; %s", ep.url, err.Error())
+			ep.setHealthy(false)
+			continue
+		}
+
+		atomic.StoreUint64(&ep.height, height)
+		if height > best {
+			best = height
+		}
+		ep.setHealthy(true)
+	}
+
+	// demote endpoints which fell too far behind the most advanced endpoint
+	for _, ep := range ed.endpoints {
+		if ep.isHealthy() && best > atomic.LoadUint64(&ep.height)+endpointLagLimit {
+			ed.log.Noticef("endpoint %s lags by more than %d blocks, marking unhealthy", ep.url, endpointLagLimit)
+			ep.setHealthy(false)
+		}
+	}
+
+	ed.refreshSticky()
+}
+
+// probe checks a single endpoint for liveness and returns its current block height.
+// A syncing node is not treated as a failure here: the lag comparison in
+// healthCheck already demotes endpoints that fall behind the best known height,
+// which covers the syncing case without having to decode eth_syncing's
+// inconsistent result shape (false when caught up, an object while syncing).
+func (ed *endpointDispatcher) probe(ep *endpoint) (uint64, error) {
+	height, err := ep.eth.BlockNumber(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// refreshSticky makes sure the sticky endpoint used for subscriptions is still healthy,
+// preferring the IPC transport over remote endpoints when one is available.
+func (ed *endpointDispatcher) refreshSticky() {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	if ed.sticky != nil && ed.sticky.isHealthy() && ed.sticky.canRoute() {
+		return
+	}
+
+	if ep := ed.pickIPC(); ep != nil {
+		ed.sticky = ep
+		return
+	}
+
+	for _, ep := range ed.endpoints {
+		if ep.isHealthy() && ep.canRoute() {
+			ed.sticky = ep
+			return
+		}
+	}
+}
+
+// pickIPC returns the first healthy IPC endpoint whose circuit is closed or open
+// past its cooldown (see canRoute), or nil when no IPC transport is configured or usable.
+func (ed *endpointDispatcher) pickIPC() *endpoint {
+	for _, ep := range ed.endpoints {
+		if ep.transport == transportIPC && ep.isHealthy() && ep.canRoute() {
+			return ep
+		}
+	}
+	return nil
+}
+
+// pickRead selects the next endpoint for a stateless read call using round-robin
+// among the currently healthy endpoints whose circuit breaker allows traffic.
+func (ed *endpointDispatcher) pickRead() *endpoint {
+	total := len(ed.endpoints)
+	for i := 0; i < total; i++ {
+		idx := int(atomic.AddUint32(&ed.next, 1)) % total
+		ep := ed.endpoints[idx]
+		if ep.isHealthy() && ep.canRoute() {
+			return ep
+		}
+	}
+
+	// no healthy endpoint found; fall back to the first known endpoint anyway
+	return ed.endpoints[0]
+}
+
+// pickForMethod selects the endpoint a given JSON-RPC method should be routed to,
+// preferring the IPC transport for heavy calls when one is available.
+func (ed *endpointDispatcher) pickForMethod(method string) *endpoint {
+	if isHeavyMethod(method) {
+		if ep := ed.pickIPC(); ep != nil {
+			return ep
+		}
+	}
+	return ed.pickRead()
+}
+
+// pickSticky returns the endpoint subscriptions should be bound to.
+func (ed *endpointDispatcher) pickSticky() *endpoint {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
+	return ed.sticky
+}
+
+// retryBackoff returns a jittered delay before the given retry attempt (0-based)
+// is sent to the next endpoint, spreading out retries across concurrent callers.
+func retryBackoff(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+	jitter := time.Duration(rand.Int63n(int64(callRetryBackoffBase)))
+	return time.Duration(attempt)*callRetryBackoffBase + jitter
+}
+
+// Call routes a JSON-RPC call to a healthy endpoint, transparently retrying
+// it on the next endpoint when the current one fails at the transport level.
+func (ed *endpointDispatcher) Call(result interface{}, method string, args ...interface{}) error {
+	return ed.CallContext(context.Background(), result, method, args...)
+}
+
+// CallContext routes a JSON-RPC call to a healthy endpoint the same way Call does,
+// but propagates the given context's deadline/cancellation to the transport, retries
+// on the next endpoint (with a bounded budget and jittered backoff) when a transport
+// fails, demotes flapping endpoints via their circuit breaker, and records a
+// rpc_requests_total/rpc_duration_seconds-equivalent observation for the call.
+func (ed *endpointDispatcher) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	start := time.Now()
+	var lastErr error
+	tried := make(map[*endpoint]bool)
+
+	for attempt := 0; attempt < len(ed.endpoints); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				ed.metrics.record(method, classifyCallError(ctx.Err()), time.Since(start))
+				return ctx.Err()
+			case <-time.After(retryBackoff(attempt)):
+			}
+		}
+
+		ep := ed.pickForMethod(method)
+		if tried[ep] {
+			continue
+		}
+		tried[ep] = true
+
+		if !ep.claimProbe() {
+			// lost the race for the single half-open probe slot (or the circuit
+			// is still cooling down); move on without a real call or a failure
+			// recorded against the endpoint.
+			lastErr = fmt.Errorf("endpoint %s circuit is open", ep.url)
+			continue
+		}
+
+		lastErr = ep.rpc.CallContext(ctx, result, method, args...)
+		if lastErr == nil {
+			ep.recordSuccess()
+			ed.metrics.record(method, callStatusOK, time.Since(start))
+			return nil
+		}
+
+		// the node answered with a JSON-RPC error (e.g. execution reverted); the
+		// endpoint itself works, so return the error without demoting it or retrying
+		var rpcErr rpc.Error
+		if errors.As(lastErr, &rpcErr) {
+			ep.recordSuccess()
+			ed.metrics.record(method, classifyCallError(lastErr), time.Since(start))
+			return lastErr
+		}
+		ed.log.Errorf("call %s failed on endpoint %s; %s", method, ep.url, lastErr.Error())
+		ep.recordFailure()
+		ep.setHealthy(false)
+	}
+
+	ed.metrics.record(method, classifyCallError(lastErr), time.Since(start))
+	return lastErr
+}
+
+// EthSubscribe opens a subscription against the current sticky endpoint so that
+// all the notifications of a single logical subscription land on the same connection.
+func (ed *endpointDispatcher) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	ep := ed.pickSticky()
+	if ep == nil {
+		return nil, fmt.Errorf("no healthy endpoint available for subscription")
+	}
+	return ep.rpc.EthSubscribe(ctx, channel, args...)
+}
+
+// resubscribeElsewhere marks the given endpoint unhealthy and moves the sticky
+// subscription target to another healthy endpoint, if available.
+func (ed *endpointDispatcher) resubscribeElsewhere(failed *endpoint) {
+	failed.setHealthy(false)
+	failed.recordFailure()
+	ed.refreshSticky()
+}
+
+// primary returns the first healthy endpoint, used for direct *eth.Client access
+// required by smart contract bindings.
+func (ed *endpointDispatcher) primary() *endpoint {
+	for _, ep := range ed.endpoints {
+		if ep.isHealthy() {
+			return ep
+		}
+	}
+	return ed.endpoints[0]
+}
+
+// health returns a point-in-time health snapshot of every known endpoint.
+func (ed *endpointDispatcher) health() []EndpointHealth {
+	out := make([]EndpointHealth, 0, len(ed.endpoints))
+	for _, ep := range ed.endpoints {
+		ep.mu.Lock()
+		circuit := ep.circuit
+		ep.mu.Unlock()
+
+		out = append(out, EndpointHealth{
+			Url:       ep.url,
+			Transport: ep.transport.String(),
+			Healthy:   ep.isHealthy(),
+			Circuit:   circuit.String(),
+			Height:    atomic.LoadUint64(&ep.height),
+		})
+	}
+	return out
+}
+
+// Close terminates all the underlying RPC connections.
+func (ed *endpointDispatcher) Close() {
+	for _, ep := range ed.endpoints {
+		ep.rpc.Close()
+		ep.eth.Close()
+	}
+}
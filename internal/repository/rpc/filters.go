@@ -0,0 +1,298 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	etc "github.com/ethereum/go-ethereum/core/types"
+)
+
+// logConsumerChannelCapacity bounds the per-consumer log channel so a single slow
+// GraphQL subscriber can not stall the other consumers of the same filter group.
+const logConsumerChannelCapacity = 256
+
+// logGroupResubscribeTick represents the delay between re-subscribe attempts after
+// the upstream log subscription of a filter group fails.
+const logGroupResubscribeTick = 30 * time.Second
+
+// Subscription represents a handle to an active log subscription; callers use it
+// to detect a terminal failure and to release the subscription once no longer needed.
+type Subscription interface {
+	Unsubscribe()
+	Err() <-chan error
+}
+
+// logConsumer is a single in-process listener of a shared logGroup.
+type logConsumer struct {
+	out    chan etc.Log
+	err    chan error
+	once   sync.Once
+	remove func()
+}
+
+// Unsubscribe detaches the consumer from its filter group.
+func (c *logConsumer) Unsubscribe() {
+	c.once.Do(func() {
+		c.remove()
+		close(c.err)
+	})
+}
+
+// Err reports a terminal failure of the underlying filter group.
+func (c *logConsumer) Err() <-chan error {
+	return c.err
+}
+
+// logGroup multiplexes a single upstream eth_subscribe("logs", ...) subscription
+// to every in-process consumer registered for the same filter query.
+type logGroup struct {
+	chain *ChainBridge
+	query ethereum.FilterQuery
+
+	mu        sync.Mutex
+	consumers map[*logConsumer]bool
+	closed    bool // true once the last consumer has left and the group is tearing down
+	sub       ethereum.Subscription
+	upstream  chan etc.Log
+	sigClose  chan bool
+}
+
+// FilterAPI exposes log filter queries and subscriptions, multiplexing one upstream
+// subscription per distinct filter query across every in-process consumer.
+type FilterAPI struct {
+	chain *ChainBridge
+
+	mu     sync.Mutex
+	groups map[string]*logGroup
+}
+
+// filterAPI lazily builds the FilterAPI singleton for the bridge.
+func (chain *ChainBridge) filterAPI() *FilterAPI {
+	v, _, _ := chain.cg.Do("filter-api", func() (interface{}, error) {
+		if chain.filters == nil {
+			chain.filters = &FilterAPI{chain: chain, groups: make(map[string]*logGroup)}
+		}
+		return chain.filters, nil
+	})
+	return v.(*FilterAPI)
+}
+
+// Filter returns the shared FilterAPI instance of the bridge.
+func (chain *ChainBridge) Filter() *FilterAPI {
+	return chain.filterAPI()
+}
+
+// GetLogs returns the logs matching the given filter query.
+func (api *FilterAPI) GetLogs(ctx context.Context, q ethereum.FilterQuery) ([]etc.Log, error) {
+	logs, err := api.chain.dispatcher.primary().eth.FilterLogs(ctx, q)
+	if err != nil {
+		api.chain.log.Errorf("can not load logs; %s", err.Error())
+		return nil, err
+	}
+	return logs, nil
+}
+
+// filterKeyFallbackCounter hands out the unique suffix filterKey falls back to
+// when a query fails to marshal, so distinct un-marshalable queries never
+// collide onto the same group.
+var filterKeyFallbackCounter uint64
+
+// filterKey builds a canonical lookup key for a filter query so equivalent
+// queries from different consumers share the same upstream subscription.
+func filterKey(q ethereum.FilterQuery) string {
+	raw, err := json.Marshal(q)
+	if err != nil {
+		// extremely unlikely for a well-formed FilterQuery; fall back to a key
+		// unique to this call, so the query still works just without
+		// de-duplication, instead of risking distinct queries sharing a group
+		return fmt.Sprintf("raw:%d", atomic.AddUint64(&filterKeyFallbackCounter, 1))
+	}
+	return string(raw)
+}
+
+// SubscribeLogs registers a new consumer for the given filter query and returns
+// a bounded, drop-oldest channel of matching logs along with a Subscription handle.
+// If the existing group for this query is concurrently tearing down (its last
+// consumer just unsubscribed), a fresh group is created instead of registering
+// onto one that will never dispatch to it.
+func (api *FilterAPI) SubscribeLogs(ctx context.Context, q ethereum.FilterQuery) (<-chan etc.Log, Subscription, error) {
+	key := filterKey(q)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		default:
+		}
+
+		api.mu.Lock()
+		g, ok := api.groups[key]
+		if !ok {
+			g = &logGroup{
+				chain:     api.chain,
+				query:     q,
+				consumers: make(map[*logConsumer]bool),
+				sigClose:  make(chan bool, 1),
+			}
+			api.groups[key] = g
+			go g.run(func() { api.removeGroup(key, g) })
+		}
+		api.mu.Unlock()
+
+		out, sub, err, closed := g.addConsumer()
+		if closed {
+			// the group we just found is already tearing down; give its
+			// goroutine a moment to finish removing itself from api.groups
+			// before retrying, instead of busy-spinning on api.mu.
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		return out, sub, err
+	}
+}
+
+// removeGroup drops a filter group once its last consumer has unsubscribed, but
+// only if it is still the group registered for key (a newer group may already
+// have replaced it by the time this runs).
+func (api *FilterAPI) removeGroup(key string, g *logGroup) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if api.groups[key] == g {
+		delete(api.groups, key)
+	}
+}
+
+// addConsumer registers a new bounded, drop-oldest channel against the group,
+// reporting closed=true instead if the group has already begun shutting down.
+func (g *logGroup) addConsumer() (out <-chan etc.Log, sub Subscription, err error, closed bool) {
+	c := &logConsumer{
+		out: make(chan etc.Log, logConsumerChannelCapacity),
+		err: make(chan error, 1),
+	}
+
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil, nil, nil, true
+	}
+
+	c.remove = func() {
+		g.mu.Lock()
+		delete(g.consumers, c)
+		empty := len(g.consumers) == 0
+		if empty {
+			g.closed = true
+		}
+		g.mu.Unlock()
+
+		if empty {
+			g.sigClose <- true
+		}
+	}
+	g.consumers[c] = true
+	g.mu.Unlock()
+
+	return c.out, c, nil, false
+}
+
+// run drives the upstream subscription of the group, re-subscribing on failure
+// until the group has no more consumers left (mirrors ChainBridge.observeBlocks).
+func (g *logGroup) run(onDone func()) {
+	defer onDone()
+
+	ok := g.subscribe()
+	for {
+		// re-subscribe if the subscription ref is not valid
+		if !ok {
+			tm := time.NewTimer(logGroupResubscribeTick)
+			select {
+			case <-g.sigClose:
+				tm.Stop()
+				return
+			case <-tm.C:
+				ok = g.subscribe()
+				continue
+			}
+		}
+
+		select {
+		case <-g.sigClose:
+			g.sub.Unsubscribe()
+			return
+		case lg := <-g.upstream:
+			g.dispatch(lg)
+		case err := <-g.sub.Err():
+			g.chain.log.Errorf("log subscription failed; %s", err.Error())
+			g.broadcastErr(err)
+			ok = false
+		}
+	}
+}
+
+// subscribe opens the upstream log subscription against the current primary endpoint.
+func (g *logGroup) subscribe() bool {
+	g.upstream = make(chan etc.Log, logConsumerChannelCapacity)
+
+	sub, err := g.chain.dispatcher.primary().eth.SubscribeFilterLogs(context.Background(), g.query, g.upstream)
+	if err != nil {
+		g.chain.log.Errorf("can not subscribe to logs; %s", err.Error())
+		return false
+	}
+
+	g.sub = sub
+	return true
+}
+
+// dispatch fans a single log out to every registered consumer, dropping the oldest
+// buffered entry of a consumer whose channel is full instead of blocking the group.
+func (g *logGroup) dispatch(lg etc.Log) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for c := range g.consumers {
+		select {
+		case c.out <- lg:
+		default:
+			// slow consumer; drop the oldest entry to make room and retry once
+			select {
+			case <-c.out:
+			default:
+			}
+			select {
+			case c.out <- lg:
+			default:
+			}
+		}
+	}
+}
+
+// broadcastErr notifies every registered consumer that the group subscription failed.
+func (g *logGroup) broadcastErr(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for c := range g.consumers {
+		select {
+		case c.err <- err:
+		default:
+		}
+	}
+}
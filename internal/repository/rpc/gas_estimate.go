@@ -0,0 +1,182 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// defaultRPCGasCap bounds the gas amount considered during client-side gas
+// estimation by default, mirroring the upstream node's --rpc.gascap.
+const defaultRPCGasCap uint64 = 50_000_000
+
+// intrinsicGas is the minimal amount of gas any transaction requires, used as
+// the lower bound of the client-side binary search (geth's params.TxGas).
+const intrinsicGas uint64 = 21000
+
+// OverrideAccount mirrors geth's eth_call state override object, letting a caller
+// pin the balance, nonce, code or storage of an account for the duration of a call.
+type OverrideAccount struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64              `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes               `json:"code,omitempty"`
+	State     *map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// blockGasLimit is the subset of eth_getBlockByNumber we need to bound
+// the client-side gas estimation search.
+type blockGasLimit struct {
+	GasLimit hexutil.Uint64 `json:"gasLimit"`
+}
+
+// GasEstimateWithOverrides estimates the gas required to perform the transaction
+// described by trx the same way GasEstimate does, but drives the estimation with a
+// client-side binary search over eth_call instead of relying on eth_estimateGas,
+// so a tight estimate can still be produced when the upstream node refuses to
+// estimate (e.g. the call reverts, the sender balance is insufficient, or the
+// node enforces its own stricter gas cap). overrides, if given, are forwarded to
+// every eth_call the same way geth's state override object works.
+func (chain *ChainBridge) GasEstimateWithOverrides(trx *TransactionArgs, overrides map[common.Address]OverrideAccount) (*hexutil.Uint64, error) {
+	chain.log.Debugf("estimating gas via client-side binary search")
+	trx.normalize()
+
+	hi, err := chain.gasEstimateUpperBound(trx)
+	if err != nil {
+		return nil, err
+	}
+
+	lo := intrinsicGas - 1
+	fits := func(gas uint64) (bool, []byte, error) {
+		return chain.gasEstimateFits(trx, gas, overrides)
+	}
+
+	// make sure the upper bound actually fits before searching, surfacing the
+	// revert reason immediately if even the cap fails
+	ok, data, err := fits(hi)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, decodeRevertReason(data, fmt.Errorf("gas required exceeds allowance of %d", hi))
+	}
+
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		ok, _, err := fits(mid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	val := hexutil.Uint64(hi)
+	return &val, nil
+}
+
+// gasEstimateUpperBound resolves the starting upper bound of the binary search:
+// the smaller of the caller-supplied gas, the configured RPCGasCap, and the gas
+// limit of the latest block.
+func (chain *ChainBridge) gasEstimateUpperBound(trx *TransactionArgs) (uint64, error) {
+	hi := chain.rpcGasCap
+	if hi == 0 {
+		hi = defaultRPCGasCap
+	}
+
+	var head blockGasLimit
+	if err := chain.dispatcher.Call(&head, "eth_getBlockByNumber", BlockTypeLatest, false); err != nil {
+		chain.log.Errorf("can not load latest block gas limit; %s", err.Error())
+		return 0, err
+	}
+
+	if uint64(head.GasLimit) < hi {
+		hi = uint64(head.GasLimit)
+	}
+
+	if trx.Gas != nil && uint64(*trx.Gas) < hi {
+		hi = uint64(*trx.Gas)
+	}
+
+	return hi, nil
+}
+
+// gasEstimateFits issues an eth_call against the "latest" block tag with the candidate
+// gas amount and the given state overrides, reporting whether the call fits. The tag
+// is resolved by the node on every call, so the calls of one search are not pinned
+// to a single block.
+func (chain *ChainBridge) gasEstimateFits(trx *TransactionArgs, gas uint64, overrides map[common.Address]OverrideAccount) (bool, []byte, error) {
+	g := hexutil.Uint64(gas)
+	call := *trx
+	call.Gas = &g
+
+	var result hexutil.Bytes
+	var err error
+	if len(overrides) > 0 {
+		err = chain.dispatcher.Call(&result, "eth_call", &call, BlockTypeLatest, overrides)
+	} else {
+		err = chain.dispatcher.Call(&result, "eth_call", &call, BlockTypeLatest)
+	}
+
+	if err == nil {
+		return true, result, nil
+	}
+
+	// a revert or out-of-gas is expected while narrowing the range down; any
+	// other transport-level error should abort the search
+	if revertData, ok := executionErrorData(err); ok {
+		return false, revertData, nil
+	}
+	return false, nil, err
+}
+
+// executionErrorData tells apart an EVM execution failure (revert, out of gas)
+// from a transport-level RPC error which should abort the estimation, extracting
+// the returned revert data when the node provides it.
+func executionErrorData(err error) ([]byte, bool) {
+	type dataError interface {
+		Error() string
+		ErrorCode() int
+		ErrorData() interface{}
+	}
+
+	de, ok := err.(dataError)
+	if !ok {
+		return nil, false
+	}
+
+	if raw, ok := de.ErrorData().(string); ok {
+		if data, err := hexutil.Decode(raw); err == nil {
+			return data, true
+		}
+	}
+	return nil, true
+}
+
+// decodeRevertReason tries to decode the standard Error(string) revert reason out
+// of the return data of a failed eth_call, falling back to the given error.
+func decodeRevertReason(data []byte, fallback error) error {
+	reason, err := abi.UnpackRevert(data)
+	if err != nil {
+		return fallback
+	}
+	return fmt.Errorf("execution reverted: %s", reason)
+}
@@ -0,0 +1,255 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	etc "github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// errNoFeeHistory is returned when the node responds to eth_feeHistory with an
+// empty sample, which should not normally happen once a chain has any blocks.
+var errNoFeeHistory = fmt.Errorf("eth_feeHistory returned an empty sample")
+
+// gasOracleFeeHistoryBlocks is the number of trailing blocks sampled via eth_feeHistory
+// on every refresh.
+const gasOracleFeeHistoryBlocks = 20
+
+// gasOracleFallbackPollTick is used to refresh the oracle on a timer when the
+// transport does not support the newHeads subscription (plain HTTP).
+const gasOracleFallbackPollTick = 15 * time.Second
+
+// gasOracleResubscribeTick is the delay between subscription attempts once the
+// newHeads subscription used to drive the oracle fails.
+const gasOracleResubscribeTick = 30 * time.Second
+
+// gasOracleTipPercentile selects the suggested priority fee out of the sampled
+// reward percentiles requested from eth_feeHistory (index into feeHistoryPercentiles).
+const gasOracleTipPercentile = 1 // the median of [10, 50, 90]
+
+// feeHistoryPercentiles are the reward percentiles requested from eth_feeHistory.
+var feeHistoryPercentiles = []float64{10, 50, 90}
+
+// GasPriceSuggestion is the set of fee values the gas price oracle currently recommends.
+type GasPriceSuggestion struct {
+	BaseFee              *hexutil.Big
+	MaxPriorityFeePerGas *hexutil.Big
+	GasPrice             hexutil.Big
+	UpdatedAt            time.Time
+}
+
+// feeHistoryResult mirrors the eth_feeHistory JSON-RPC response.
+type feeHistoryResult struct {
+	OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+}
+
+// GasPriceOracle maintains a hot cached fee suggestion, refreshed by the chain's
+// head subscription, falling back to a polling ticker over a plain HTTP transport.
+type GasPriceOracle struct {
+	chain *ChainBridge
+	sg    singleflight.Group
+
+	mu  sync.RWMutex
+	cur GasPriceSuggestion
+
+	updates uint64 // atomic; gas_oracle_updates_total
+}
+
+// gasOracle lazily builds the GasPriceOracle singleton for the bridge.
+func (chain *ChainBridge) gasOracle() *GasPriceOracle {
+	v, _, _ := chain.cg.Do("gas-oracle", func() (interface{}, error) {
+		if chain.oracle == nil {
+			chain.oracle = &GasPriceOracle{chain: chain}
+		}
+		return chain.oracle, nil
+	})
+	return v.(*GasPriceOracle)
+}
+
+// runGasOracle starts the background refresh loop of the oracle.
+func (chain *ChainBridge) runGasOracle() {
+	chain.wg.Add(1)
+	go chain.gasOracle().run(chain.sigClose, chain.wg)
+}
+
+// run drives the oracle refresh loop: it prefers reacting to new chain heads, and
+// falls back to a polling ticker when the head subscription can not be established.
+func (o *GasPriceOracle) run(sigClose chan bool, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	o.refresh()
+
+	heads := make(chan *etc.Header, rpcHeadProxyChannelCapacity)
+	sub, err := o.chain.dispatcher.EthSubscribe(context.Background(), heads, "newHeads")
+	if err != nil {
+		o.chain.log.Noticef("gas price oracle falling back to polling; %s", err.Error())
+		o.runPolling(sigClose)
+		return
+	}
+
+	for {
+		select {
+		case <-sigClose:
+			sub.Unsubscribe()
+			return
+		case <-heads:
+			o.refresh()
+		case err := <-sub.Err():
+			o.chain.log.Errorf("gas price oracle subscription failed; %s", err.Error())
+
+			tm := time.NewTimer(gasOracleResubscribeTick)
+			select {
+			case <-sigClose:
+				tm.Stop()
+				return
+			case <-tm.C:
+			}
+
+			heads = make(chan *etc.Header, rpcHeadProxyChannelCapacity)
+			sub, err = o.chain.dispatcher.EthSubscribe(context.Background(), heads, "newHeads")
+			if err != nil {
+				o.chain.log.Noticef("gas price oracle falling back to polling; %s", err.Error())
+				o.runPolling(sigClose)
+				return
+			}
+		}
+	}
+}
+
+// runPolling refreshes the oracle on a fixed interval, used when the transport
+// does not support the newHeads subscription.
+func (o *GasPriceOracle) runPolling(sigClose chan bool) {
+	tick := time.NewTicker(gasOracleFallbackPollTick)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-sigClose:
+			return
+		case <-tick.C:
+			o.refresh()
+		}
+	}
+}
+
+// refresh coalesces concurrent callers behind a single in-flight update and
+// recomputes the cached suggestion from a fresh eth_feeHistory sample.
+func (o *GasPriceOracle) refresh() {
+	_, _, _ = o.sg.Do("refresh", func() (interface{}, error) {
+		suggestion, err := o.sample()
+		if err != nil {
+			o.chain.log.Errorf("gas price oracle refresh failed; %s", err.Error())
+			return nil, err
+		}
+
+		o.mu.Lock()
+		o.cur = suggestion
+		o.mu.Unlock()
+
+		atomic.AddUint64(&o.updates, 1)
+		return nil, nil
+	})
+}
+
+// sample pulls a fresh eth_feeHistory window and derives the fee suggestion from it.
+func (o *GasPriceOracle) sample() (GasPriceSuggestion, error) {
+	var hist feeHistoryResult
+	err := o.chain.dispatcher.Call(&hist, "eth_feeHistory", hexutil.Uint64(gasOracleFeeHistoryBlocks), BlockTypeLatest, feeHistoryPercentiles)
+	if err != nil {
+		return GasPriceSuggestion{}, err
+	}
+
+	if len(hist.BaseFeePerGas) == 0 {
+		return GasPriceSuggestion{}, errNoFeeHistory
+	}
+
+	// the last entry of baseFeePerGas is the predicted base fee of the next block
+	baseFee := hist.BaseFeePerGas[len(hist.BaseFeePerGas)-1].ToInt()
+	tip := medianTip(hist.Reward)
+
+	gasPrice := new(big.Int).Add(baseFee, tip)
+
+	return GasPriceSuggestion{
+		BaseFee:              (*hexutil.Big)(baseFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+		GasPrice:             hexutil.Big(*gasPrice),
+		UpdatedAt:            time.Now(),
+	}, nil
+}
+
+// medianTip averages the median-percentile (gasOracleTipPercentile) reward sampled
+// across every block returned by eth_feeHistory.
+func medianTip(rewards [][]*hexutil.Big) *big.Int {
+	if len(rewards) == 0 {
+		return big.NewInt(0)
+	}
+
+	sum := new(big.Int)
+	count := 0
+	for _, blockRewards := range rewards {
+		if len(blockRewards) <= gasOracleTipPercentile {
+			continue
+		}
+		sum.Add(sum, blockRewards[gasOracleTipPercentile].ToInt())
+		count++
+	}
+
+	if count == 0 {
+		return big.NewInt(0)
+	}
+	return sum.Div(sum, big.NewInt(int64(count)))
+}
+
+// SuggestFees returns the currently cached fee suggestion.
+func (chain *ChainBridge) SuggestFees() GasPriceSuggestion {
+	o := chain.gasOracle()
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.cur
+}
+
+// GasOracleStats reports the update counter and staleness of the gas price oracle.
+type GasOracleStats struct {
+	UpdatesTotal uint64
+	StaleSeconds float64
+}
+
+// GasOracleStats exposes gas_oracle_updates_total and gas_oracle_stale_seconds.
+func (api *MetricsAPI) GasOracleStats() GasOracleStats {
+	o := api.chain.gasOracle()
+	o.mu.RLock()
+	updatedAt := o.cur.UpdatedAt
+	o.mu.RUnlock()
+
+	stale := 0.0
+	if !updatedAt.IsZero() {
+		stale = time.Since(updatedAt).Seconds()
+	}
+
+	return GasOracleStats{
+		UpdatesTotal: atomic.LoadUint64(&o.updates),
+		StaleSeconds: stale,
+	}
+}
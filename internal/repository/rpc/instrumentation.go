@@ -0,0 +1,189 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer is the package-wide OpenTelemetry tracer used by CallInstrumented to
+// emit per-call spans; it defers entirely to whatever TracerProvider the host
+// application has configured via otel.SetTracerProvider (a no-op one if none).
+var tracer = otel.Tracer("galaxy-graphql/internal/repository/rpc")
+
+// callStatus classifies the outcome of an instrumented JSON-RPC call.
+type callStatus string
+
+const (
+	// callStatusOK marks a call that returned without error.
+	callStatusOK callStatus = "ok"
+	// callStatusError marks a call that failed with an RPC-returned error
+	// (e.g. execution reverted, invalid params).
+	callStatusError callStatus = "error"
+	// callStatusRetryable marks a call that failed with a transport-level
+	// error (EOF, connection reset, timeout) an upper layer may retry.
+	callStatusRetryable callStatus = "retryable"
+)
+
+// methodStat accumulates the rpc_requests_total{method,status} counters and the
+// rpc_duration_seconds{method} running total for a single JSON-RPC method.
+type methodStat struct {
+	requests map[callStatus]uint64
+	duration time.Duration
+	calls    uint64
+}
+
+// callMetrics is the in-process equivalent of the rpc_requests_total/rpc_duration_seconds
+// metrics described by operators; it is intentionally dependency-free so the rpc
+// package does not need to pull in a metrics client to be instrumented.
+type callMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*methodStat
+}
+
+// newCallMetrics creates an empty metrics recorder.
+func newCallMetrics() *callMetrics {
+	return &callMetrics{methods: make(map[string]*methodStat)}
+}
+
+// record accumulates a single call observation for the given method.
+func (m *callMetrics) record(method string, status callStatus, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.methods[method]
+	if !ok {
+		s = &methodStat{requests: make(map[callStatus]uint64)}
+		m.methods[method] = s
+	}
+	s.requests[status]++
+	s.duration += elapsed
+	s.calls++
+}
+
+// MethodCallStats is a point-in-time snapshot of the counters accumulated for
+// a single JSON-RPC method.
+type MethodCallStats struct {
+	Method           string
+	OK               uint64
+	Errors           uint64
+	Retryable        uint64
+	AverageLatencyMs float64
+}
+
+// snapshot returns a stable copy of the current per-method metrics.
+func (m *callMetrics) snapshot() []MethodCallStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]MethodCallStats, 0, len(m.methods))
+	for method, s := range m.methods {
+		avg := 0.0
+		if s.calls > 0 {
+			avg = float64(s.duration.Milliseconds()) / float64(s.calls)
+		}
+		out = append(out, MethodCallStats{
+			Method:           method,
+			OK:               s.requests[callStatusOK],
+			Errors:           s.requests[callStatusError],
+			Retryable:        s.requests[callStatusRetryable],
+			AverageLatencyMs: avg,
+		})
+	}
+	return out
+}
+
+// argHash fingerprints a call's arguments into a short, stable hex digest, so a
+// trace line can correlate repeated calls to the same method/args without
+// logging potentially large or sensitive argument values in full.
+func argHash(args ...interface{}) string {
+	h := fnv.New64a()
+	for _, a := range args {
+		fmt.Fprintf(h, "%v|", a)
+	}
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// CallInstrumented calls a JSON-RPC method through the dispatcher the same way
+// Call does - which already records per-method request/status counters and call
+// duration for every routed call - additionally wrapping it in an OpenTelemetry
+// span carrying the method name, an argument hash, and the error class as
+// attributes, and propagating the given context (including the span itself)
+// to the underlying transport.
+func (chain *ChainBridge) CallInstrumented(ctx context.Context, out interface{}, method string, args ...interface{}) error {
+	ctx, span := tracer.Start(ctx, "rpc."+method, trace.WithAttributes(
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.arg_hash", argHash(args...)),
+	))
+	defer span.End()
+
+	err := chain.dispatcher.CallContext(ctx, out, method, args...)
+
+	status := callStatusOK
+	if err != nil {
+		status = classifyCallError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.String("rpc.error_class", string(status)))
+
+	return err
+}
+
+// classifyCallError tells apart a transport-level failure from an RPC-returned error.
+func classifyCallError(err error) callStatus {
+	if isRetryableTransportError(err) {
+		return callStatusRetryable
+	}
+	return callStatusError
+}
+
+// isRetryableTransportError reports whether err looks like a transient transport
+// failure (EOF, connection reset, timeout) rather than an RPC-level error.
+func isRetryableTransportError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") ||
+		strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "i/o timeout")
+}
+
+// MethodStats returns a point-in-time snapshot of the rpc_requests_total/
+// rpc_duration_seconds-equivalent counters accumulated across every JSON-RPC
+// call routed through the dispatcher, so operators can spot things like an
+// eth_estimateGas latency spike or an eth_gasPrice error rate increase.
+func (api *MetricsAPI) MethodStats() []MethodCallStats {
+	return api.chain.dispatcher.metrics.snapshot()
+}
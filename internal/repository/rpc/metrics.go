@@ -0,0 +1,38 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+// CacheStats represents the accumulated hit/miss counters of the contract read cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// MetricsAPI exposes operational counters so operators can tune cache TTLs and
+// observe per-method RPC calls and the gas price oracle without reaching into the
+// bridge internals. Endpoint health is reported by ChainBridge.Endpoints instead.
+type MetricsAPI struct {
+	chain *ChainBridge
+}
+
+// Metrics returns the MetricsAPI sub-client of the bridge.
+func (chain *ChainBridge) Metrics() *MetricsAPI {
+	return &MetricsAPI{chain: chain}
+}
+
+// CacheStats returns the current hit/miss counters of the contract read cache.
+func (api *MetricsAPI) CacheStats() CacheStats {
+	hits, misses := api.chain.cache.Stats()
+	return CacheStats{Hits: hits, Misses: misses}
+}
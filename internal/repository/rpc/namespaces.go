@@ -0,0 +1,112 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// namespaceClient holds the bridge whose shared dispatcher every per-namespace
+// sub-client uses to route its JSON-RPC calls.
+type namespaceClient struct {
+	chain *ChainBridge
+}
+
+// Call routes a namespaced JSON-RPC call through the bridge dispatcher.
+func (nc *namespaceClient) Call(result interface{}, method string, args ...interface{}) error {
+	return nc.chain.dispatcher.Call(result, method, args...)
+}
+
+// EthAPI exposes the eth_* namespace of the connected Lachesis/Opera node.
+type EthAPI struct{ namespaceClient }
+
+// NetAPI exposes the net_* namespace of the connected Lachesis/Opera node.
+type NetAPI struct{ namespaceClient }
+
+// Web3API exposes the web3_* namespace of the connected Lachesis/Opera node.
+type Web3API struct{ namespaceClient }
+
+// TraceAPI exposes the trace_* namespace of the connected Lachesis/Opera node.
+type TraceAPI struct{ namespaceClient }
+
+// SFCAPI exposes SFC contract interactions as a dedicated namespace.
+type SFCAPI struct{ namespaceClient }
+
+// DeFiAPI exposes DeFi/fMint contract interactions as a dedicated namespace.
+type DeFiAPI struct{ namespaceClient }
+
+// Eth returns the eth_* namespace sub-client.
+func (chain *ChainBridge) Eth() *EthAPI {
+	return &EthAPI{namespaceClient{chain: chain}}
+}
+
+// Net returns the net_* namespace sub-client.
+func (chain *ChainBridge) Net() *NetAPI {
+	return &NetAPI{namespaceClient{chain: chain}}
+}
+
+// Web3 returns the web3_* namespace sub-client.
+func (chain *ChainBridge) Web3() *Web3API {
+	return &Web3API{namespaceClient{chain: chain}}
+}
+
+// Trace returns the trace_* namespace sub-client.
+func (chain *ChainBridge) Trace() *TraceAPI {
+	return &TraceAPI{namespaceClient{chain: chain}}
+}
+
+// Sfc returns the SFC contract namespace sub-client.
+func (chain *ChainBridge) Sfc() *SFCAPI {
+	return &SFCAPI{namespaceClient{chain: chain}}
+}
+
+// DeFi returns the DeFi/fMint contract namespace sub-client.
+func (chain *ChainBridge) DeFi() *DeFiAPI {
+	return &DeFiAPI{namespaceClient{chain: chain}}
+}
+
+// BlockNumber returns the current block height known to the node.
+func (api *EthAPI) BlockNumber() (uint64, error) {
+	var h hexutil.Uint64
+	err := api.Call(&h, "eth_blockNumber")
+	return uint64(h), err
+}
+
+// ChainId returns the chain id reported by the node.
+func (api *EthAPI) ChainId() (uint64, error) {
+	var h hexutil.Uint64
+	err := api.Call(&h, "eth_chainId")
+	return uint64(h), err
+}
+
+// Version returns the current network id.
+func (api *NetAPI) Version() (string, error) {
+	var v string
+	err := api.Call(&v, "net_version")
+	return v, err
+}
+
+// Listening tells if the node is actively listening for network connections.
+func (api *NetAPI) Listening() (bool, error) {
+	var v bool
+	err := api.Call(&v, "net_listening")
+	return v, err
+}
+
+// ClientVersion returns the node's client version string.
+func (api *Web3API) ClientVersion() (string, error) {
+	var v string
+	err := api.Call(&v, "web3_clientVersion")
+	return v, err
+}
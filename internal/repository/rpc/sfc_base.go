@@ -19,15 +19,13 @@ package rpc
 //go:generate tools/abigen.sh --abi ./contracts/abi/sfc-tokenizer.abi --pkg contracts --type SfcTokenizer --out ./contracts/sfc_tokenizer.go
 
 import (
+	"fmt"
 	"galaxy-graphql/internal/types"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-// sfcFirstLockEpoch represents the first epoch with stake locking available.
-const sfcFirstLockEpoch uint64 = 1600
-
 // SfcVersion returns current version of the SFC contract as a single number.
 func (chain *ChainBridge) SfcVersion() (hexutil.Uint64, error) {
 	// get the version information from the contract
@@ -63,25 +61,42 @@ func (chain *ChainBridge) CurrentSealedEpoch() (hexutil.Uint64, error) {
 	return hexutil.Uint64(epoch.Uint64()), nil
 }
 
-// Epoch extract information about an epoch from SFC smart contract.
+// Epoch extract information about an epoch from SFC smart contract. Already sealed
+// epochs are immutable, so the result is cached once first read, until the whole
+// read cache is cleared when the sealed epoch advances (evictStaleEpochEntries);
+// the current, not yet sealed epoch is always read fresh from the contract.
 func (chain *ChainBridge) Epoch(id hexutil.Uint64) (*types.Epoch, error) {
-	// extract epoch snapshot
-	epo, err := chain.SfcContract().GetEpochSnapshot(nil, big.NewInt(int64(id)))
+	fetch := func() (*types.Epoch, error) {
+		epo, err := chain.SfcContract().GetEpochSnapshot(nil, big.NewInt(int64(id)))
+		if err != nil {
+			chain.log.Errorf("failed to extract epoch information: %s", err.Error())
+			return nil, err
+		}
+
+		return &types.Epoch{
+			Id:                    id,
+			EndTime:               hexutil.Uint64(epo.EndTime.Uint64()),
+			EpochFee:              (hexutil.Big)(*epo.EpochFee),
+			TotalBaseRewardWeight: (hexutil.Big)(*epo.TotalBaseRewardWeight),
+			TotalTxRewardWeight:   (hexutil.Big)(*epo.TotalTxRewardWeight),
+			BaseRewardPerSecond:   (hexutil.Big)(*epo.BaseRewardPerSecond),
+			StakeTotalAmount:      (hexutil.Big)(*epo.TotalStake),
+			TotalSupply:           (hexutil.Big)(*epo.TotalSupply),
+		}, nil
+	}
+
+	sealed, err := chain.CurrentSealedEpoch()
+	if err != nil || id > sealed {
+		return fetch()
+	}
+
+	v, err := chain.cachedForever(fmt.Sprintf("epoch:%d", id), func() (interface{}, error) {
+		return fetch()
+	})
 	if err != nil {
-		chain.log.Errorf("failed to extract epoch information: %s", err.Error())
 		return nil, err
 	}
-
-	return &types.Epoch{
-		Id:                    id,
-		EndTime:               hexutil.Uint64(epo.EndTime.Uint64()),
-		EpochFee:              (hexutil.Big)(*epo.EpochFee),
-		TotalBaseRewardWeight: (hexutil.Big)(*epo.TotalBaseRewardWeight),
-		TotalTxRewardWeight:   (hexutil.Big)(*epo.TotalTxRewardWeight),
-		BaseRewardPerSecond:   (hexutil.Big)(*epo.BaseRewardPerSecond),
-		StakeTotalAmount:      (hexutil.Big)(*epo.TotalStake),
-		TotalSupply:           (hexutil.Big)(*epo.TotalSupply),
-	}, nil
+	return v.(*types.Epoch), nil
 }
 
 // RewardsAllowed returns if the rewards can be manipulated with.
@@ -92,47 +107,91 @@ func (chain *ChainBridge) RewardsAllowed() (bool, error) {
 
 // LockingAllowed indicates if the stake locking has been enabled in SFC.
 func (chain *ChainBridge) LockingAllowed() (bool, error) {
-	// get the current sealed epoch value from the contract
-	epoch, err := chain.SfcContract().CurrentSealedEpoch(nil)
+	sfc, err := chain.SFC()
 	if err != nil {
-		chain.log.Errorf("failed to get the current sealed epoch: %s", err.Error())
 		return false, err
 	}
 
-	return epoch.Uint64() >= sfcFirstLockEpoch, nil
+	allowed, err := sfc.LockingAllowed(nil)
+	if err != nil {
+		chain.log.Errorf("failed to check if locking is allowed: %s", err.Error())
+		return false, err
+	}
+	return allowed, nil
 }
 
-// TotalStaked returns the total amount of staked tokens.
+// TotalStaked returns the total amount of staked tokens, cached per sealed epoch
+// since the total only moves on epoch boundaries.
 func (chain *ChainBridge) TotalStaked() (*big.Int, error) {
-	return chain.SfcContract().TotalStake(chain.DefaultCallOpts())
+	key, err := chain.epochScopedKey("total-staked")
+	if err != nil {
+		return chain.SfcContract().TotalStake(chain.DefaultCallOpts())
+	}
+
+	v, err := chain.cachedForever(key, func() (interface{}, error) {
+		return chain.SfcContract().TotalStake(chain.DefaultCallOpts())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*big.Int), nil
+}
+
+// sfcConstParam loads a constant SFC contract parameter through the read cache,
+// scoped to the currently detected SFC version so an upgrade invalidates it.
+func (chain *ChainBridge) sfcConstParam(name string, fetch func() (*big.Int, error)) (*big.Int, error) {
+	key, err := chain.sfcParamKey(name)
+	if err != nil {
+		return fetch()
+	}
+
+	v, err := chain.cachedForever(key, func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*big.Int), nil
 }
 
 // SfcMinValidatorStake extracts a value of minimal validator self stake.
 func (chain *ChainBridge) SfcMinValidatorStake() (*big.Int, error) {
-	return chain.SfcContract().MinSelfStake(chain.DefaultCallOpts())
+	return chain.sfcConstParam("min-validator-stake", func() (*big.Int, error) {
+		return chain.SfcContract().MinSelfStake(chain.DefaultCallOpts())
+	})
 }
 
 // SfcMaxDelegatedRatio extracts a ratio between self delegation and received stake.
 func (chain *ChainBridge) SfcMaxDelegatedRatio() (*big.Int, error) {
-	return chain.SfcContract().MaxDelegatedRatio(chain.DefaultCallOpts())
+	return chain.sfcConstParam("max-delegated-ratio", func() (*big.Int, error) {
+		return chain.SfcContract().MaxDelegatedRatio(chain.DefaultCallOpts())
+	})
 }
 
 // SfcMinLockupDuration extracts a minimal lockup duration.
 func (chain *ChainBridge) SfcMinLockupDuration() (*big.Int, error) {
-	return chain.SfcContract().MinLockupDuration(chain.DefaultCallOpts())
+	return chain.sfcConstParam("min-lockup-duration", func() (*big.Int, error) {
+		return chain.SfcContract().MinLockupDuration(chain.DefaultCallOpts())
+	})
 }
 
 // SfcMaxLockupDuration extracts a maximal lockup duration.
 func (chain *ChainBridge) SfcMaxLockupDuration() (*big.Int, error) {
-	return chain.SfcContract().MaxLockupDuration(chain.DefaultCallOpts())
+	return chain.sfcConstParam("max-lockup-duration", func() (*big.Int, error) {
+		return chain.SfcContract().MaxLockupDuration(chain.DefaultCallOpts())
+	})
 }
 
 // SfcWithdrawalPeriodEpochs extracts a minimal number of epochs between un-delegate and withdraw.
 func (chain *ChainBridge) SfcWithdrawalPeriodEpochs() (*big.Int, error) {
-	return chain.SfcContract().WithdrawalPeriodEpochs(chain.DefaultCallOpts())
+	return chain.sfcConstParam("withdrawal-period-epochs", func() (*big.Int, error) {
+		return chain.SfcContract().WithdrawalPeriodEpochs(chain.DefaultCallOpts())
+	})
 }
 
 // SfcWithdrawalPeriodTime extracts a minimal number of seconds between un-delegate and withdraw.
 func (chain *ChainBridge) SfcWithdrawalPeriodTime() (*big.Int, error) {
-	return chain.SfcContract().WithdrawalPeriodTime(chain.DefaultCallOpts())
+	return chain.sfcConstParam("withdrawal-period-time", func() (*big.Int, error) {
+		return chain.SfcContract().WithdrawalPeriodTime(chain.DefaultCallOpts())
+	})
 }
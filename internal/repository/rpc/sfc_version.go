@@ -0,0 +1,273 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"fmt"
+	"galaxy-graphql/internal/repository/rpc/contracts"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// ErrLockupNotSupported is returned by lockup related SFC calls on contract
+// versions which pre-date stake locking (SFC v1).
+var ErrLockupNotSupported = fmt.Errorf("stake locking is not supported by this SFC version")
+
+// sfcEpochSnapshot is the version-independent view of an epoch snapshot read
+// from the SFC contract, translated from the per-version generated structs.
+type sfcEpochSnapshot struct {
+	EndTime               *big.Int
+	EpochFee              *big.Int
+	TotalBaseRewardWeight *big.Int
+	TotalTxRewardWeight   *big.Int
+	BaseRewardPerSecond   *big.Int
+	TotalStake            *big.Int
+	TotalSupply           *big.Int
+}
+
+// SFC is the version-independent interface every SFC contract binding implements,
+// so callers do not need to know which SFC version is currently deployed.
+type SFC interface {
+	CurrentEpoch(opts *bind.CallOpts) (*big.Int, error)
+	CurrentSealedEpoch(opts *bind.CallOpts) (*big.Int, error)
+	GetEpochSnapshot(opts *bind.CallOpts, epoch *big.Int) (sfcEpochSnapshot, error)
+	TotalStake(opts *bind.CallOpts) (*big.Int, error)
+	MinSelfStake(opts *bind.CallOpts) (*big.Int, error)
+	MaxDelegatedRatio(opts *bind.CallOpts) (*big.Int, error)
+	WithdrawalPeriodEpochs(opts *bind.CallOpts) (*big.Int, error)
+	WithdrawalPeriodTime(opts *bind.CallOpts) (*big.Int, error)
+	MinLockupDuration(opts *bind.CallOpts) (*big.Int, error)
+	MaxLockupDuration(opts *bind.CallOpts) (*big.Int, error)
+	LockingAllowed(opts *bind.CallOpts) (bool, error)
+}
+
+// sfcV1 wraps the SFC v1.1 binding, which pre-dates stake locking.
+type sfcV1 struct{ c *contracts.SfcV1Contract }
+
+func (s *sfcV1) CurrentEpoch(opts *bind.CallOpts) (*big.Int, error)       { return s.c.CurrentEpoch(opts) }
+func (s *sfcV1) CurrentSealedEpoch(opts *bind.CallOpts) (*big.Int, error) { return s.c.CurrentSealedEpoch(opts) }
+
+func (s *sfcV1) GetEpochSnapshot(opts *bind.CallOpts, epoch *big.Int) (sfcEpochSnapshot, error) {
+	epo, err := s.c.GetEpochSnapshot(opts, epoch)
+	if err != nil {
+		return sfcEpochSnapshot{}, err
+	}
+	return sfcEpochSnapshot{
+		EndTime:               epo.EndTime,
+		EpochFee:              epo.EpochFee,
+		TotalBaseRewardWeight: epo.TotalBaseRewardWeight,
+		TotalTxRewardWeight:   epo.TotalTxRewardWeight,
+		BaseRewardPerSecond:   epo.BaseRewardPerSecond,
+		TotalStake:            epo.TotalStake,
+		TotalSupply:           epo.TotalSupply,
+	}, nil
+}
+
+func (s *sfcV1) TotalStake(opts *bind.CallOpts) (*big.Int, error)       { return s.c.TotalStake(opts) }
+func (s *sfcV1) MinSelfStake(opts *bind.CallOpts) (*big.Int, error)     { return s.c.MinSelfStake(opts) }
+func (s *sfcV1) MaxDelegatedRatio(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MaxDelegatedRatio(opts)
+}
+func (s *sfcV1) WithdrawalPeriodEpochs(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodEpochs(opts)
+}
+func (s *sfcV1) WithdrawalPeriodTime(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodTime(opts)
+}
+
+// MinLockupDuration is not available on SFC v1; lockup was introduced in v2.
+func (s *sfcV1) MinLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return nil, ErrLockupNotSupported
+}
+
+// MaxLockupDuration is not available on SFC v1; lockup was introduced in v2.
+func (s *sfcV1) MaxLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return nil, ErrLockupNotSupported
+}
+
+// LockingAllowed is always false on SFC v1.
+func (s *sfcV1) LockingAllowed(opts *bind.CallOpts) (bool, error) {
+	return false, nil
+}
+
+// sfcV2 wraps the SFC v2.0.4-rc.2 binding, which introduced stake locking.
+type sfcV2 struct{ c *contracts.SfcV2Contract }
+
+func (s *sfcV2) CurrentEpoch(opts *bind.CallOpts) (*big.Int, error)       { return s.c.CurrentEpoch(opts) }
+func (s *sfcV2) CurrentSealedEpoch(opts *bind.CallOpts) (*big.Int, error) { return s.c.CurrentSealedEpoch(opts) }
+
+func (s *sfcV2) GetEpochSnapshot(opts *bind.CallOpts, epoch *big.Int) (sfcEpochSnapshot, error) {
+	epo, err := s.c.GetEpochSnapshot(opts, epoch)
+	if err != nil {
+		return sfcEpochSnapshot{}, err
+	}
+	return sfcEpochSnapshot{
+		EndTime:               epo.EndTime,
+		EpochFee:              epo.EpochFee,
+		TotalBaseRewardWeight: epo.TotalBaseRewardWeight,
+		TotalTxRewardWeight:   epo.TotalTxRewardWeight,
+		BaseRewardPerSecond:   epo.BaseRewardPerSecond,
+		TotalStake:            epo.TotalStake,
+		TotalSupply:           epo.TotalSupply,
+	}, nil
+}
+
+func (s *sfcV2) TotalStake(opts *bind.CallOpts) (*big.Int, error)       { return s.c.TotalStake(opts) }
+func (s *sfcV2) MinSelfStake(opts *bind.CallOpts) (*big.Int, error)     { return s.c.MinSelfStake(opts) }
+func (s *sfcV2) MaxDelegatedRatio(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MaxDelegatedRatio(opts)
+}
+func (s *sfcV2) WithdrawalPeriodEpochs(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodEpochs(opts)
+}
+func (s *sfcV2) WithdrawalPeriodTime(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodTime(opts)
+}
+func (s *sfcV2) MinLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MinLockupDuration(opts)
+}
+func (s *sfcV2) MaxLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MaxLockupDuration(opts)
+}
+
+// LockingAllowed on v2+ depends on the current sealed epoch, same cut-over as v3.
+func (s *sfcV2) LockingAllowed(opts *bind.CallOpts) (bool, error) {
+	return sfcLockingAllowedBySealedEpoch(s, opts)
+}
+
+// sfcV3 wraps the SFC v3.0-rc.1 binding, the current production version.
+type sfcV3 struct{ c *contracts.SfcContract }
+
+func (s *sfcV3) CurrentEpoch(opts *bind.CallOpts) (*big.Int, error)       { return s.c.CurrentEpoch(opts) }
+func (s *sfcV3) CurrentSealedEpoch(opts *bind.CallOpts) (*big.Int, error) { return s.c.CurrentSealedEpoch(opts) }
+
+func (s *sfcV3) GetEpochSnapshot(opts *bind.CallOpts, epoch *big.Int) (sfcEpochSnapshot, error) {
+	epo, err := s.c.GetEpochSnapshot(opts, epoch)
+	if err != nil {
+		return sfcEpochSnapshot{}, err
+	}
+	return sfcEpochSnapshot{
+		EndTime:               epo.EndTime,
+		EpochFee:              epo.EpochFee,
+		TotalBaseRewardWeight: epo.TotalBaseRewardWeight,
+		TotalTxRewardWeight:   epo.TotalTxRewardWeight,
+		BaseRewardPerSecond:   epo.BaseRewardPerSecond,
+		TotalStake:            epo.TotalStake,
+		TotalSupply:           epo.TotalSupply,
+	}, nil
+}
+
+func (s *sfcV3) TotalStake(opts *bind.CallOpts) (*big.Int, error)       { return s.c.TotalStake(opts) }
+func (s *sfcV3) MinSelfStake(opts *bind.CallOpts) (*big.Int, error)     { return s.c.MinSelfStake(opts) }
+func (s *sfcV3) MaxDelegatedRatio(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MaxDelegatedRatio(opts)
+}
+func (s *sfcV3) WithdrawalPeriodEpochs(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodEpochs(opts)
+}
+func (s *sfcV3) WithdrawalPeriodTime(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.WithdrawalPeriodTime(opts)
+}
+func (s *sfcV3) MinLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MinLockupDuration(opts)
+}
+func (s *sfcV3) MaxLockupDuration(opts *bind.CallOpts) (*big.Int, error) {
+	return s.c.MaxLockupDuration(opts)
+}
+func (s *sfcV3) LockingAllowed(opts *bind.CallOpts) (bool, error) {
+	return sfcLockingAllowedBySealedEpoch(s, opts)
+}
+
+// sfcFirstLockEpoch represents the first epoch with stake locking available,
+// applicable to the v2 and v3 SFC contracts.
+const sfcFirstLockEpoch uint64 = 1600
+
+// sfcLockingAllowedBySealedEpoch implements the shared v2/v3 locking cut-over rule:
+// locking becomes available once the current sealed epoch reaches sfcFirstLockEpoch.
+func sfcLockingAllowedBySealedEpoch(s SFC, opts *bind.CallOpts) (bool, error) {
+	epoch, err := s.CurrentSealedEpoch(opts)
+	if err != nil {
+		return false, err
+	}
+	return epoch.Uint64() >= sfcFirstLockEpoch, nil
+}
+
+// SFC returns a version-aware SFC contract wrapper, lazily probing the deployed
+// contract version on first use and dispatching to the matching binding.
+func (chain *ChainBridge) SFC() (SFC, error) {
+	sfc, err, _ := chain.cg.Do("sfc-version-probe", func() (interface{}, error) {
+		if chain.sfc != nil {
+			return chain.sfc, nil
+		}
+
+		ver, err := chain.SfcVersion()
+		if err != nil {
+			chain.log.Criticalf("failed to probe the SFC contract version; %s", err.Error())
+			return nil, err
+		}
+
+		impl, err := chain.newSfcByVersion(uint64(ver))
+		if err != nil {
+			return nil, err
+		}
+
+		chain.sfc = impl
+		return impl, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sfc.(SFC), nil
+}
+
+// sfcMajorVersion extracts the major version out of a raw SfcVersion() number.
+//
+// The SFC contract's version() getter returns its 3 version bytes as ASCII
+// digits rather than raw numbers (e.g. "304" is returned as the byte triplet
+// ['3','0','4'], not [3, 0, 4]), so the top byte of the (major<<16|minor<<8|patch)
+// number SfcVersion() builds is an ASCII digit code (e.g. '3' == 0x33 == 51),
+// not the major version itself. Decode it back into the actual digit.
+func sfcMajorVersion(version uint64) uint64 {
+	b := byte(version >> 16)
+	if b >= '0' && b <= '9' {
+		return uint64(b - '0')
+	}
+	return uint64(b)
+}
+
+// newSfcByVersion instantiates the SFC binding matching the given contract version.
+func (chain *ChainBridge) newSfcByVersion(version uint64) (SFC, error) {
+	addr := chain.sfcConfig.SFCContract
+	backend := chain.dispatcher.primary().eth
+
+	// major 3+ is the current production line, major 2 introduced locking,
+	// major 1 predates it
+	switch sfcMajorVersion(version) {
+	case 0, 1:
+		c, err := contracts.NewSfcV1Contract(addr, backend)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate SFC v1 contract; %s", err.Error())
+		}
+		return &sfcV1{c: c}, nil
+	case 2:
+		c, err := contracts.NewSfcV2Contract(addr, backend)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate SFC v2 contract; %s", err.Error())
+		}
+		return &sfcV2{c: c}, nil
+	default:
+		return &sfcV3{c: chain.SfcContract()}, nil
+	}
+}
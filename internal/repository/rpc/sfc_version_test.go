@@ -0,0 +1,27 @@
+package rpc
+
+import "testing"
+
+// TestSfcMajorVersion asserts that the ASCII-digit version bytes the SFC
+// contract actually returns (e.g. "304" as the byte triplet '3','0','4')
+// decode to the v1/v2/v3 major version newSfcByVersion dispatches on,
+// instead of the raw ASCII code.
+func TestSfcMajorVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint64
+		want    uint64
+	}{
+		{"v1 patch", uint64('1')<<16 | uint64('1')<<8 | uint64('0'), 1},
+		{"v2 rc", uint64('2')<<16 | uint64('0')<<8 | uint64('4'), 2},
+		{"v3 rc", uint64('3')<<16 | uint64('0')<<8 | uint64('1'), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sfcMajorVersion(tt.version); got != tt.want {
+				t.Errorf("sfcMajorVersion(%#x) = %d, want %d", tt.version, got, tt.want)
+			}
+		})
+	}
+}
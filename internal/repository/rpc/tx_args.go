@@ -0,0 +1,85 @@
+/*
+Package rpc implements bridge to Lachesis full node API interface.
+
+We recommend using local IPC for fast and the most efficient inter-process communication between the API server
+and an Opera/Lachesis node. Any remote RPC connection will work, but the performance may be significantly degraded
+by extra networking overhead of remote RPC calls.
+
+You should also consider security implications of opening Lachesis RPC interface for a remote access.
+If you considering it as your deployment strategy, you should establish encrypted channel between the API server
+and Lachesis RPC interface with connection limited to specified endpoints.
+
+We strongly discourage opening Lachesis RPC interface for unrestricted Internet access.
+*/
+package rpc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionArgs mirrors geth's eth_call/eth_estimateGas transaction argument
+// object, accepting both the legacy and the EIP-1559 fee fields so callers can
+// estimate gas for either transaction type instead of just From/To/Value/Data.
+type TransactionArgs struct {
+	From  *common.Address `json:"from,omitempty"`
+	To    *common.Address `json:"to,omitempty"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+	Nonce *hexutil.Uint64 `json:"nonce,omitempty"`
+	Gas   *hexutil.Uint64 `json:"gas,omitempty"`
+
+	GasPrice             *hexutil.Big `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas,omitempty"`
+
+	Data  *hexutil.Bytes `json:"data,omitempty"`
+	Input *hexutil.Bytes `json:"input,omitempty"`
+
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+}
+
+// normalize makes Input take precedence over Data, the same way geth's
+// TransactionArgs does, while still mirroring the chosen call data into Data
+// for nodes which only understand the legacy field.
+func (args *TransactionArgs) normalize() {
+	if args.Input != nil {
+		args.Data = args.Input
+		return
+	}
+	if args.Data != nil {
+		args.Input = args.Data
+	}
+}
+
+// setDefaults normalizes Input/Data and, only if the caller set MaxFeePerGas or
+// MaxPriorityFeePerGas (and no GasPrice), fills in whichever of those two is still
+// missing from the chain's gas price oracle; GasPrice itself is never filled in.
+// A caller who supplied no fee field at all is left fee-free: eth_estimateGas
+// does not require one, and silently injecting a non-zero gasPrice would make
+// nodes enforce balance >= gas*gasPrice, breaking estimates for unfunded or
+// contract senders that previously succeeded. A caller who already supplied
+// GasPrice is assumed to want a legacy transaction and is left untouched.
+func (args *TransactionArgs) setDefaults(chain *ChainBridge) {
+	args.normalize()
+
+	if args.GasPrice != nil {
+		return
+	}
+
+	if args.MaxFeePerGas == nil && args.MaxPriorityFeePerGas == nil {
+		return
+	}
+
+	suggestion := chain.SuggestFees()
+	if args.MaxPriorityFeePerGas == nil && suggestion.MaxPriorityFeePerGas != nil {
+		args.MaxPriorityFeePerGas = suggestion.MaxPriorityFeePerGas
+	}
+	if args.MaxFeePerGas == nil && args.MaxPriorityFeePerGas != nil && suggestion.BaseFee != nil {
+		fee := new(big.Int).Add(suggestion.BaseFee.ToInt(), args.MaxPriorityFeePerGas.ToInt())
+		args.MaxFeePerGas = (*hexutil.Big)(fee)
+	}
+}
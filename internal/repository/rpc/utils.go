@@ -16,18 +16,25 @@ package rpc
 import (
 	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
-// GasPrice pulls the current amount of WEI for single Gas.
+// GasPrice returns the currently suggested legacy gas price, served from the gas
+// price oracle's cached suggestion so it costs no per-request RPC round trip.
+// See GasPriceOracle/SuggestFees for the EIP-1559 aware suggestion.
 func (chain *ChainBridge) GasPrice() (hexutil.Big, error) {
-	// keep track of the operation
-	chain.log.Debugf("checking current gas price")
+	suggestion := chain.SuggestFees()
+	if !suggestion.UpdatedAt.IsZero() {
+		return suggestion.GasPrice, nil
+	}
+
+	// the oracle has not produced a sample yet (e.g. right after startup);
+	// fall back to a direct call so the first caller is not left empty-handed
+	chain.log.Debugf("gas price oracle not warmed up yet, calling the node directly")
 
-	// call for data
 	var price hexutil.Big
-	err := chain.rpc.Call(&price, "eth_gasPrice")
+	err := chain.dispatcher.Call(&price, "eth_gasPrice")
 	if err != nil {
 		chain.log.Error("current gas price could not be obtained")
 		return price, err
@@ -37,18 +44,20 @@ func (chain *ChainBridge) GasPrice() (hexutil.Big, error) {
 }
 
 // GasEstimate calculates the estimated amount of Gas required to perform
-// transaction described by the input params.
-func (chain *ChainBridge) GasEstimate(trx *struct {
-	From  *common.Address
-	To    *common.Address
-	Value *hexutil.Big
-	Data  *string
-}) (*hexutil.Uint64, error) {
+// transaction described by trx, optionally pinned at a specific pending,
+// latest, or historical block/hash via blockNrOrHash.
+func (chain *ChainBridge) GasEstimate(trx *TransactionArgs, blockNrOrHash *ethrpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
 	// keep track of the operation
 	chain.log.Debugf("calling for gas amount estimation")
+	trx.setDefaults(chain)
 
 	var val hexutil.Uint64
-	err := chain.rpc.Call(&val, "eth_estimateGas", trx)
+	var err error
+	if blockNrOrHash != nil {
+		err = chain.dispatcher.Call(&val, "eth_estimateGas", trx, blockNrOrHash)
+	} else {
+		err = chain.dispatcher.Call(&val, "eth_estimateGas", trx)
+	}
 	if err != nil {
 		// missing required argument? incompatibility between old and new RPC API
 		if strings.Contains(err.Error(), "missing value") {
@@ -67,17 +76,13 @@ func (chain *ChainBridge) GasEstimate(trx *struct {
 // transaction described by the input params with specifying the block on which the calculation
 // should happen (new RPC API compatibility).
 // @TODO Replace the old gas estimate call once the API gets upgraded on all nodes.
-func (chain *ChainBridge) GasEstimateWithBlock(trx *struct {
-	From  *common.Address
-	To    *common.Address
-	Value *hexutil.Big
-	Data  *string
-}) (*hexutil.Uint64, error) {
+func (chain *ChainBridge) GasEstimateWithBlock(trx *TransactionArgs) (*hexutil.Uint64, error) {
 	// keep track of the operation
 	chain.log.Debugf("calling for gas amount estimation with block details")
+	trx.setDefaults(chain)
 
 	var val hexutil.Uint64
-	err := chain.rpc.Call(&val, "eth_estimateGas", trx, BlockTypeLatest)
+	err := chain.dispatcher.Call(&val, "eth_estimateGas", trx, BlockTypeLatest)
 	if err != nil {
 		// return error
 		chain.log.Errorf("can not estimate gas; %s", err.Error())